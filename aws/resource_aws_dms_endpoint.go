@@ -3,12 +3,18 @@ package aws
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
 	dms "github.com/aws/aws-sdk-go/service/databasemigrationservice"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
@@ -25,6 +31,12 @@ func resourceAwsDmsEndpoint() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"certificate_arn": {
 				Type:         schema.TypeString,
@@ -47,8 +59,10 @@ func resourceAwsDmsEndpoint() *schema.Resource {
 				ValidateFunc: validateDmsEndpointId,
 			},
 			"service_access_role": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Deprecated:    "for S3/DynamoDB endpoints use s3_settings.service_access_role_arn or dynamodb_settings.service_access_role_arn instead",
+				ConflictsWith: []string{"s3_settings", "dynamodb_settings"},
 			},
 			"endpoint_type": {
 				Type:     schema.TypeString,
@@ -73,9 +87,16 @@ func resourceAwsDmsEndpoint() *schema.Resource {
 					"sybase",
 					"sqlserver",
 					"s3",
+					"mongodb",
+					"kinesis",
+					"kafka",
 				}, false),
 			},
 			"extra_connection_attributes": {
+				// Superseded by s3_settings for engine_name = "s3"; still the only way
+				// to pass extra connection attributes for the relational engines (mysql,
+				// oracle, postgres, mariadb, aurora, aurora-postgresql, redshift, sybase,
+				// sqlserver). It is not sent for dynamodb, mongodb, kinesis or kafka.
 				Type:     schema.TypeString,
 				Computed: true,
 				Optional: true,
@@ -121,17 +142,526 @@ func resourceAwsDmsEndpoint() *schema.Resource {
 				Optional: true,
 			},
 			"bucket_name": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Deprecated:    "use s3_settings.bucket_name instead",
+				ConflictsWith: []string{"s3_settings"},
 			},
 			"bucket_folder": {
-				Type:     schema.TypeString,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Deprecated:    "use s3_settings.bucket_folder instead",
+				ConflictsWith: []string{"s3_settings"},
+			},
+			"s3_settings": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"bucket_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"bucket_folder": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"service_access_role_arn": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"compression_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "NONE",
+							ValidateFunc: validation.StringInSlice([]string{
+								"NONE",
+								"GZIP",
+							}, false),
+						},
+						"csv_delimiter": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  ",",
+						},
+						"csv_row_delimiter": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "\\n",
+						},
+						"external_table_definition": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"data_format": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"csv",
+								"parquet",
+							}, false),
+						},
+						"encryption_mode": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								dms.EncryptionModeValueSseS3,
+								dms.EncryptionModeValueSseKms,
+							}, false),
+						},
+						"server_side_encryption_kms_key_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"date_partition_enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"service_endpoint_url": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"region_override": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"force_path_style": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"disable_ssl": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"access_key_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"secret_access_key": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+			"dynamodb_settings": {
+				Type:     schema.TypeList,
 				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"service_access_role_arn": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"mongodb_settings": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"auth_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  dms.AuthTypeValuePassword,
+							ValidateFunc: validation.StringInSlice([]string{
+								dms.AuthTypeValueNo,
+								dms.AuthTypeValuePassword,
+							}, false),
+						},
+						"auth_mechanism": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  dms.AuthMechanismValueDefault,
+							ValidateFunc: validation.StringInSlice([]string{
+								dms.AuthMechanismValueDefault,
+								dms.AuthMechanismValueMongodbCr,
+								dms.AuthMechanismValueScramSha1,
+							}, false),
+						},
+						"auth_source": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "admin",
+						},
+						"nesting_level": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  dms.NestingLevelValueNone,
+							ValidateFunc: validation.StringInSlice([]string{
+								dms.NestingLevelValueNone,
+								dms.NestingLevelValueOne,
+							}, false),
+						},
+						"extract_doc_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "false",
+						},
+						"docs_to_investigate": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "1000",
+						},
+						"kms_key_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"kinesis_settings": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"stream_arn": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"message_format": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  dms.MessageFormatValueJson,
+							ValidateFunc: validation.StringInSlice([]string{
+								dms.MessageFormatValueJson,
+								dms.MessageFormatValueJsonUnformatted,
+							}, false),
+						},
+						"service_access_role_arn": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"kafka_settings": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"broker": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"topic": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"test_connection": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"replication_instance_arn": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateArn,
+						},
+						"timeout": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  5,
+						},
+						"rollback_on_failure": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
 			},
 		},
 	}
 }
 
+func expandDmsS3Settings(l []interface{}) *dms.S3Settings {
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	m := l[0].(map[string]interface{})
+
+	s3Settings := &dms.S3Settings{
+		BucketName:           aws.String(m["bucket_name"].(string)),
+		ServiceAccessRoleArn: aws.String(m["service_access_role_arn"].(string)),
+		CompressionType:      aws.String(m["compression_type"].(string)),
+		CsvDelimiter:         aws.String(m["csv_delimiter"].(string)),
+		CsvRowDelimiter:      aws.String(m["csv_row_delimiter"].(string)),
+	}
+
+	if v, ok := m["bucket_folder"].(string); ok && v != "" {
+		s3Settings.BucketFolder = aws.String(v)
+	}
+	if v, ok := m["external_table_definition"].(string); ok && v != "" {
+		s3Settings.ExternalTableDefinition = aws.String(v)
+	}
+	if v, ok := m["data_format"].(string); ok && v != "" {
+		s3Settings.DataFormat = aws.String(v)
+	}
+	if v, ok := m["encryption_mode"].(string); ok && v != "" {
+		s3Settings.EncryptionMode = aws.String(v)
+	}
+	if v, ok := m["server_side_encryption_kms_key_id"].(string); ok && v != "" {
+		s3Settings.ServerSideEncryptionKmsKeyId = aws.String(v)
+	}
+	if v, ok := m["date_partition_enabled"].(bool); ok {
+		s3Settings.DatePartitionEnabled = aws.Bool(v)
+	}
+
+	return s3Settings
+}
+
+// s3CompatibleEndpointSettings holds the s3_settings fields used to reach an
+// S3-compatible object store (MinIO, DigitalOcean Spaces, Ceph): the overrides
+// that have no equivalent on dms.S3Settings, plus the bucket name and service
+// access role needed to check the bucket.
+type s3CompatibleEndpointSettings struct {
+	ServiceEndpointURL   string
+	RegionOverride       string
+	ForcePathStyle       bool
+	DisableSSL           bool
+	BucketName           string
+	ServiceAccessRoleArn string
+	AccessKeyID          string
+	SecretAccessKey      string
+}
+
+func expandDmsS3CompatibleSettings(l []interface{}) *s3CompatibleEndpointSettings {
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	m := l[0].(map[string]interface{})
+
+	url, _ := m["service_endpoint_url"].(string)
+	if url == "" {
+		return nil
+	}
+
+	return &s3CompatibleEndpointSettings{
+		ServiceEndpointURL:   url,
+		RegionOverride:       m["region_override"].(string),
+		ForcePathStyle:       m["force_path_style"].(bool),
+		DisableSSL:           m["disable_ssl"].(bool),
+		BucketName:           m["bucket_name"].(string),
+		ServiceAccessRoleArn: m["service_access_role_arn"].(string),
+		AccessKeyID:          m["access_key_id"].(string),
+		SecretAccessKey:      m["secret_access_key"].(string),
+	}
+}
+
+// validateS3CompatibleEndpoint verifies that the bucket backing an S3-compatible
+// s3_settings block exists and is reachable before DMS ever attempts to use it,
+// so misconfiguration surfaces at apply time instead of when a task runs. The
+// S3-compatible store (MinIO, DigitalOcean Spaces, Ceph) almost never shares
+// credentials with the Terraform runner's ambient AWS chain, so prefer static
+// access_key_id/secret_access_key when given, fall back to assuming
+// service_access_role_arn, and only default to the ambient chain as a last resort.
+func validateS3CompatibleEndpoint(settings *s3CompatibleEndpointSettings) error {
+	region := settings.RegionOverride
+	if region == "" {
+		region = endpoints.UsEast1RegionID
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating S3-compatible client for %q: %s", settings.ServiceEndpointURL, err)
+	}
+
+	config := &aws.Config{
+		Endpoint:         aws.String(settings.ServiceEndpointURL),
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(settings.ForcePathStyle),
+		DisableSSL:       aws.Bool(settings.DisableSSL),
+	}
+
+	switch {
+	case settings.AccessKeyID != "" && settings.SecretAccessKey != "":
+		config.Credentials = credentials.NewStaticCredentials(settings.AccessKeyID, settings.SecretAccessKey, "")
+	case settings.ServiceAccessRoleArn != "":
+		config.Credentials = stscreds.NewCredentials(sess, settings.ServiceAccessRoleArn)
+	}
+
+	s3conn := s3.New(sess, config)
+	if _, err := s3conn.HeadBucket(&s3.HeadBucketInput{
+		Bucket: aws.String(settings.BucketName),
+	}); err != nil {
+		return fmt.Errorf("error verifying S3-compatible bucket %q at %q: %s", settings.BucketName, settings.ServiceEndpointURL, err)
+	}
+
+	return nil
+}
+
+func flattenDmsS3Settings(s3Settings *dms.S3Settings) []map[string]interface{} {
+	if s3Settings == nil {
+		return []map[string]interface{}{}
+	}
+
+	m := map[string]interface{}{
+		"bucket_name":                       aws.StringValue(s3Settings.BucketName),
+		"bucket_folder":                     aws.StringValue(s3Settings.BucketFolder),
+		"service_access_role_arn":           aws.StringValue(s3Settings.ServiceAccessRoleArn),
+		"compression_type":                  aws.StringValue(s3Settings.CompressionType),
+		"csv_delimiter":                     aws.StringValue(s3Settings.CsvDelimiter),
+		"csv_row_delimiter":                 aws.StringValue(s3Settings.CsvRowDelimiter),
+		"external_table_definition":         aws.StringValue(s3Settings.ExternalTableDefinition),
+		"data_format":                       aws.StringValue(s3Settings.DataFormat),
+		"encryption_mode":                   aws.StringValue(s3Settings.EncryptionMode),
+		"server_side_encryption_kms_key_id": aws.StringValue(s3Settings.ServerSideEncryptionKmsKeyId),
+		"date_partition_enabled":            aws.BoolValue(s3Settings.DatePartitionEnabled),
+	}
+
+	return []map[string]interface{}{m}
+}
+
+func expandDmsDynamoDbSettings(l []interface{}) *dms.DynamoDbSettings {
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	m := l[0].(map[string]interface{})
+
+	return &dms.DynamoDbSettings{
+		ServiceAccessRoleArn: aws.String(m["service_access_role_arn"].(string)),
+	}
+}
+
+func flattenDmsDynamoDbSettings(dynamoDbSettings *dms.DynamoDbSettings) []map[string]interface{} {
+	if dynamoDbSettings == nil {
+		return []map[string]interface{}{}
+	}
+
+	m := map[string]interface{}{
+		"service_access_role_arn": aws.StringValue(dynamoDbSettings.ServiceAccessRoleArn),
+	}
+
+	return []map[string]interface{}{m}
+}
+
+func expandDmsMongoDbSettings(l []interface{}) *dms.MongoDbSettings {
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	m := l[0].(map[string]interface{})
+
+	mongoDbSettings := &dms.MongoDbSettings{
+		AuthType:          aws.String(m["auth_type"].(string)),
+		AuthMechanism:     aws.String(m["auth_mechanism"].(string)),
+		AuthSource:        aws.String(m["auth_source"].(string)),
+		NestingLevel:      aws.String(m["nesting_level"].(string)),
+		ExtractDocId:      aws.String(m["extract_doc_id"].(string)),
+		DocsToInvestigate: aws.String(m["docs_to_investigate"].(string)),
+	}
+
+	if v, ok := m["kms_key_id"].(string); ok && v != "" {
+		mongoDbSettings.KmsKeyId = aws.String(v)
+	}
+
+	return mongoDbSettings
+}
+
+func flattenDmsMongoDbSettings(mongoDbSettings *dms.MongoDbSettings) []map[string]interface{} {
+	if mongoDbSettings == nil {
+		return []map[string]interface{}{}
+	}
+
+	m := map[string]interface{}{
+		"auth_type":           aws.StringValue(mongoDbSettings.AuthType),
+		"auth_mechanism":      aws.StringValue(mongoDbSettings.AuthMechanism),
+		"auth_source":         aws.StringValue(mongoDbSettings.AuthSource),
+		"nesting_level":       aws.StringValue(mongoDbSettings.NestingLevel),
+		"extract_doc_id":      aws.StringValue(mongoDbSettings.ExtractDocId),
+		"docs_to_investigate": aws.StringValue(mongoDbSettings.DocsToInvestigate),
+		"kms_key_id":          aws.StringValue(mongoDbSettings.KmsKeyId),
+	}
+
+	return []map[string]interface{}{m}
+}
+
+func expandDmsKinesisSettings(l []interface{}) *dms.KinesisSettings {
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	m := l[0].(map[string]interface{})
+
+	kinesisSettings := &dms.KinesisSettings{
+		MessageFormat: aws.String(m["message_format"].(string)),
+	}
+
+	if v, ok := m["stream_arn"].(string); ok && v != "" {
+		kinesisSettings.StreamArn = aws.String(v)
+	}
+	if v, ok := m["service_access_role_arn"].(string); ok && v != "" {
+		kinesisSettings.ServiceAccessRoleArn = aws.String(v)
+	}
+
+	return kinesisSettings
+}
+
+func flattenDmsKinesisSettings(kinesisSettings *dms.KinesisSettings) []map[string]interface{} {
+	if kinesisSettings == nil {
+		return []map[string]interface{}{}
+	}
+
+	m := map[string]interface{}{
+		"stream_arn":              aws.StringValue(kinesisSettings.StreamArn),
+		"message_format":          aws.StringValue(kinesisSettings.MessageFormat),
+		"service_access_role_arn": aws.StringValue(kinesisSettings.ServiceAccessRoleArn),
+	}
+
+	return []map[string]interface{}{m}
+}
+
+func expandDmsKafkaSettings(l []interface{}) *dms.KafkaSettings {
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	m := l[0].(map[string]interface{})
+
+	kafkaSettings := &dms.KafkaSettings{
+		Broker: aws.String(m["broker"].(string)),
+	}
+
+	if v, ok := m["topic"].(string); ok && v != "" {
+		kafkaSettings.Topic = aws.String(v)
+	}
+
+	return kafkaSettings
+}
+
+func flattenDmsKafkaSettings(kafkaSettings *dms.KafkaSettings) []map[string]interface{} {
+	if kafkaSettings == nil {
+		return []map[string]interface{}{}
+	}
+
+	m := map[string]interface{}{
+		"broker": aws.StringValue(kafkaSettings.Broker),
+		"topic":  aws.StringValue(kafkaSettings.Topic),
+	}
+
+	return []map[string]interface{}{m}
+}
+
 func resourceAwsDmsEndpointCreate(d *schema.ResourceData, meta interface{}) error {
 	conn := meta.(*AWSClient).dmsconn
 
@@ -145,37 +675,57 @@ func resourceAwsDmsEndpointCreate(d *schema.ResourceData, meta interface{}) erro
 	switch d.Get("engine_name").(string) {
 	// if dynamodb then add required params
 	case "dynamodb":
-		request.DynamoDbSettings = &dms.DynamoDbSettings{
-			ServiceAccessRoleArn: aws.String(d.Get("service_access_role").(string)),
+		if v, ok := d.GetOk("dynamodb_settings"); ok {
+			request.DynamoDbSettings = expandDmsDynamoDbSettings(v.([]interface{}))
+		} else {
+			request.DynamoDbSettings = &dms.DynamoDbSettings{
+				ServiceAccessRoleArn: aws.String(d.Get("service_access_role").(string)),
+			}
 		}
 	case "s3":
-		request.S3Settings = &dms.S3Settings{
-			BucketName:           aws.String(d.Get("bucket_name").(string)),
-			BucketFolder:         aws.String(d.Get("bucket_folder").(string)),
-			ServiceAccessRoleArn: aws.String(d.Get("service_access_role").(string)),
+		if v, ok := d.GetOk("s3_settings"); ok {
+			s3SettingsList := v.([]interface{})
+			request.S3Settings = expandDmsS3Settings(s3SettingsList)
 
-			// By default extra variables (should be set):
-			CompressionType: aws.String("GZIP"),
-			CsvDelimiter:    aws.String(","),
-			CsvRowDelimiter: aws.String("\\n"),
-		}
-
-		// if extra_connection_attributes is set. Then parse the varaiables.
-		if v, ok := d.GetOk("extra_connection_attributes"); ok {
-			elems := strings.Split(v.(string), ";")
-			if len(elems) > 0 {
-				for _, elem := range elems {
-					vals := strings.Split(elem, "=")
-					if strings.Contains(strings.ToLower(vals[0]), "compressiontype") {
-						request.S3Settings.CompressionType = aws.String(vals[1])
-					} else if strings.Contains(strings.ToLower(vals[0]), "csvdelimiter") {
-						request.S3Settings.CsvDelimiter = aws.String(vals[1])
-					} else if strings.Contains(strings.ToLower(vals[0]), "csvrowdelimiter") {
-						request.S3Settings.CsvRowDelimiter = aws.String(vals[1])
-					}
+			if compat := expandDmsS3CompatibleSettings(s3SettingsList); compat != nil {
+				if err := validateS3CompatibleEndpoint(compat); err != nil {
+					return err
 				}
+				request.ExtraConnectionAttributes = aws.String(fmt.Sprintf("serviceEndpoint=%s", compat.ServiceEndpointURL))
 			}
+		} else {
+			request.S3Settings = &dms.S3Settings{
+				BucketName:           aws.String(d.Get("bucket_name").(string)),
+				BucketFolder:         aws.String(d.Get("bucket_folder").(string)),
+				ServiceAccessRoleArn: aws.String(d.Get("service_access_role").(string)),
+
+				// By default extra variables (should be set):
+				CompressionType: aws.String("GZIP"),
+				CsvDelimiter:    aws.String(","),
+				CsvRowDelimiter: aws.String("\\n"),
+			}
+		}
+
+	case "mongodb":
+		mongoDbSettings := expandDmsMongoDbSettings(d.Get("mongodb_settings").([]interface{}))
+		if mongoDbSettings == nil {
+			// mongodb_settings is optional, but server_name/port/username/password
+			// are always required for this engine, so fall back to a zero-value
+			// struct rather than dereferencing a nil pointer below.
+			mongoDbSettings = &dms.MongoDbSettings{}
+		}
+		mongoDbSettings.ServerName = aws.String(d.Get("server_name").(string))
+		mongoDbSettings.Port = aws.Int64(int64(d.Get("port").(int)))
+		mongoDbSettings.Username = aws.String(d.Get("username").(string))
+		mongoDbSettings.Password = aws.String(d.Get("password").(string))
+		if v, ok := d.GetOk("database_name"); ok {
+			mongoDbSettings.DatabaseName = aws.String(v.(string))
 		}
+		request.MongoDbSettings = mongoDbSettings
+	case "kinesis":
+		request.KinesisSettings = expandDmsKinesisSettings(d.Get("kinesis_settings").([]interface{}))
+	case "kafka":
+		request.KafkaSettings = expandDmsKafkaSettings(d.Get("kafka_settings").([]interface{}))
 
 	default:
 		request.Password = aws.String(d.Get("password").(string))
@@ -203,26 +753,25 @@ func resourceAwsDmsEndpointCreate(d *schema.ResourceData, meta interface{}) erro
 
 	log.Println("[DEBUG] DMS create endpoint:", request)
 
-	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
-		if _, err := conn.CreateEndpoint(request); err != nil {
-			if awserr, ok := err.(awserr.Error); ok {
-				switch awserr.Code() {
-				case "AccessDeniedFault":
-					return resource.RetryableError(awserr)
-				}
-			}
-			// Didn't recognize the error, so shouldn't retry.
-			return resource.NonRetryableError(err)
-		}
-		// Successful delete
-		return nil
+	err := dmsRetryBackoff(d.Timeout(schema.TimeoutCreate), func() error {
+		_, err := conn.CreateEndpoint(request)
+		return err
 	})
 	if err != nil {
 		return err
 	}
 
 	d.SetId(d.Get("endpoint_id").(string))
-	return resourceAwsDmsEndpointRead(d, meta)
+
+	if err := resourceAwsDmsEndpointRead(d, meta); err != nil {
+		return err
+	}
+
+	if v, ok := d.GetOk("test_connection"); ok {
+		return resourceAwsDmsEndpointTestConnection(d, v.([]interface{}), meta, true)
+	}
+
+	return nil
 }
 
 func resourceAwsDmsEndpointRead(d *schema.ResourceData, meta interface{}) error {
@@ -289,39 +838,79 @@ func resourceAwsDmsEndpointUpdate(d *schema.ResourceData, meta interface{}) erro
 		hasChanges = true
 	}
 
+	if d.HasChange("tags") {
+		err := dmsSetTags(d.Get("endpoint_arn").(string), d, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	// s3 doesn't read password/port/server_name/username off the top-level
+	// ModifyEndpointInput either, so skip the generic blocks below for it too.
+	skipGenericConnectionFields := false
+
 	switch d.Get("engine_name").(string) {
 	case "dynamodb":
-		if d.HasChange("service_access_role") {
+		if d.HasChange("dynamodb_settings") {
+			request.DynamoDbSettings = expandDmsDynamoDbSettings(d.Get("dynamodb_settings").([]interface{}))
+			hasChanges = true
+		} else if d.HasChange("service_access_role") {
 			request.DynamoDbSettings = &dms.DynamoDbSettings{
 				ServiceAccessRoleArn: aws.String(d.Get("service_access_role").(string)),
 			}
 			hasChanges = true
 		}
 	case "s3":
-		if d.HasChange("service_access_role") || d.HasChange("bucket_name") || d.HasChange("bucket_folder") || d.HasChange("extra_connection_attributes") {
+		skipGenericConnectionFields = true
+
+		if d.HasChange("s3_settings") {
+			s3SettingsList := d.Get("s3_settings").([]interface{})
+			request.S3Settings = expandDmsS3Settings(s3SettingsList)
+
+			if compat := expandDmsS3CompatibleSettings(s3SettingsList); compat != nil {
+				if err := validateS3CompatibleEndpoint(compat); err != nil {
+					return err
+				}
+				request.ExtraConnectionAttributes = aws.String(fmt.Sprintf("serviceEndpoint=%s", compat.ServiceEndpointURL))
+			}
+
+			hasChanges = true
+		} else if d.HasChange("service_access_role") || d.HasChange("bucket_name") || d.HasChange("bucket_folder") {
 			request.S3Settings = &dms.S3Settings{
 				ServiceAccessRoleArn: aws.String(d.Get("service_access_role").(string)),
 				BucketFolder:         aws.String(d.Get("bucket_folder").(string)),
 				BucketName:           aws.String(d.Get("bucket_name").(string)),
 			}
 
-			elems := strings.Split(d.Get("extra_connection_attributes").(string), ";")
-			if len(elems) > 0 {
-				for _, elem := range elems {
-					vals := strings.Split(elem, "=")
-					if strings.Contains(strings.ToLower(vals[0]), "compressiontype") {
-						request.S3Settings.CompressionType = aws.String(vals[1])
-					} else if strings.Contains(strings.ToLower(vals[0]), "csvdelimiter") {
-						request.S3Settings.CsvDelimiter = aws.String(vals[1])
-					} else if strings.Contains(strings.ToLower(vals[0]), "csvrowdelimiter") {
-						request.S3Settings.CsvRowDelimiter = aws.String(vals[1])
-					}
-				}
+			hasChanges = true
+		}
+	case "mongodb":
+		if d.HasChange("mongodb_settings") || d.HasChange("server_name") || d.HasChange("port") ||
+			d.HasChange("username") || d.HasChange("password") || d.HasChange("database_name") {
+			mongoDbSettings := expandDmsMongoDbSettings(d.Get("mongodb_settings").([]interface{}))
+			if mongoDbSettings == nil {
+				mongoDbSettings = &dms.MongoDbSettings{}
 			}
-
+			mongoDbSettings.ServerName = aws.String(d.Get("server_name").(string))
+			mongoDbSettings.Port = aws.Int64(int64(d.Get("port").(int)))
+			mongoDbSettings.Username = aws.String(d.Get("username").(string))
+			mongoDbSettings.Password = aws.String(d.Get("password").(string))
+			if v, ok := d.GetOk("database_name"); ok {
+				mongoDbSettings.DatabaseName = aws.String(v.(string))
+			}
+			request.MongoDbSettings = mongoDbSettings
+			hasChanges = true
+		}
+		skipGenericConnectionFields = true
+	case "kinesis":
+		if d.HasChange("kinesis_settings") {
+			request.KinesisSettings = expandDmsKinesisSettings(d.Get("kinesis_settings").([]interface{}))
+			hasChanges = true
+		}
+	case "kafka":
+		if d.HasChange("kafka_settings") {
+			request.KafkaSettings = expandDmsKafkaSettings(d.Get("kafka_settings").([]interface{}))
 			hasChanges = true
-
-			goto DONE
 		}
 	default:
 		if d.HasChange("extra_connection_attributes") {
@@ -330,17 +919,17 @@ func resourceAwsDmsEndpointUpdate(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
-	if d.HasChange("password") {
+	if d.HasChange("password") && !skipGenericConnectionFields {
 		request.Password = aws.String(d.Get("password").(string))
 		hasChanges = true
 	}
 
-	if d.HasChange("port") {
+	if d.HasChange("port") && !skipGenericConnectionFields {
 		request.Port = aws.Int64(int64(d.Get("port").(int)))
 		hasChanges = true
 	}
 
-	if d.HasChange("server_name") {
+	if d.HasChange("server_name") && !skipGenericConnectionFields {
 		request.ServerName = aws.String(d.Get("server_name").(string))
 		hasChanges = true
 	}
@@ -350,28 +939,29 @@ func resourceAwsDmsEndpointUpdate(d *schema.ResourceData, meta interface{}) erro
 		hasChanges = true
 	}
 
-	if d.HasChange("username") {
+	if d.HasChange("username") && !skipGenericConnectionFields {
 		request.Username = aws.String(d.Get("username").(string))
 		hasChanges = true
 	}
 
-	if d.HasChange("tags") {
-		err := dmsSetTags(d.Get("endpoint_arn").(string), d, meta)
-		if err != nil {
-			return err
-		}
-	}
-
-DONE:
 	if hasChanges {
 		log.Println("[DEBUG] DMS update endpoint:", request)
 
-		_, err := conn.ModifyEndpoint(request)
+		err := dmsRetryBackoff(d.Timeout(schema.TimeoutUpdate), func() error {
+			_, err := conn.ModifyEndpoint(request)
+			return err
+		})
 		if err != nil {
 			return err
 		}
 
-		return resourceAwsDmsEndpointRead(d, meta)
+		if err := resourceAwsDmsEndpointRead(d, meta); err != nil {
+			return err
+		}
+	}
+
+	if v, ok := d.GetOk("test_connection"); ok && (hasChanges || d.HasChange("test_connection")) {
+		return resourceAwsDmsEndpointTestConnection(d, v.([]interface{}), meta, false)
 	}
 
 	return nil
@@ -386,7 +976,10 @@ func resourceAwsDmsEndpointDelete(d *schema.ResourceData, meta interface{}) erro
 
 	log.Printf("[DEBUG] DMS delete endpoint: %#v", request)
 
-	_, err := conn.DeleteEndpoint(request)
+	err := dmsRetryBackoff(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := conn.DeleteEndpoint(request)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -394,6 +987,145 @@ func resourceAwsDmsEndpointDelete(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
+// isDmsRetryableError classifies awserr codes that DMS commonly returns while
+// an endpoint is transitioning state, or while IAM role propagation is still
+// in progress after a freshly created aws_iam_role, as safe to retry.
+func isDmsRetryableError(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+
+	switch awsErr.Code() {
+	case "AccessDeniedFault",
+		"InvalidResourceStateFault",
+		"InvalidParameterValueException",
+		"ThrottlingException",
+		"RequestLimitExceeded":
+		return true
+	}
+
+	return false
+}
+
+// dmsRetryBackoff retries f, which should wrap a single DMS API call, using
+// exponential backoff (base 2s, capped at 30s, jittered) for as long as it
+// keeps returning an error isDmsRetryableError considers transient, until
+// timeout elapses. This gives DMS's IAM-propagation and state-transition
+// errors more room to clear than resource.Retry's built-in backoff, which
+// caps at 10s with no jitter.
+func dmsRetryBackoff(timeout time.Duration, f func() error) error {
+	const (
+		baseDelay = 2 * time.Second
+		maxDelay  = 30 * time.Second
+	)
+
+	deadline := time.Now().Add(timeout)
+	delay := baseDelay
+
+	for {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if !isDmsRetryableError(err) {
+			return err
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return err
+		}
+
+		jittered := delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+		time.Sleep(jittered)
+
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+// resourceAwsDmsEndpointTestConnection tests connectivity from the given replication
+// instance to the endpoint and waits for the connection to reach a terminal status,
+// returning an error if the test fails. rollback_on_failure only takes effect when
+// allowRollback is true: it's meant to undo a just-created, never-used endpoint, not
+// to delete a previously-healthy endpoint out from under an Update call just because
+// a connectivity re-check transiently failed.
+func resourceAwsDmsEndpointTestConnection(d *schema.ResourceData, testConnection []interface{}, meta interface{}, allowRollback bool) error {
+	conn := meta.(*AWSClient).dmsconn
+
+	m := testConnection[0].(map[string]interface{})
+	endpointArn := d.Get("endpoint_arn").(string)
+	replicationInstanceArn := m["replication_instance_arn"].(string)
+	timeout := time.Duration(m["timeout"].(int)) * time.Minute
+	rollbackOnFailure := m["rollback_on_failure"].(bool)
+
+	_, err := conn.TestConnection(&dms.TestConnectionInput{
+		EndpointArn:            aws.String(endpointArn),
+		ReplicationInstanceArn: aws.String(replicationInstanceArn),
+	})
+	if err != nil {
+		if awserr, ok := err.(awserr.Error); ok && awserr.Code() == "ResourceAlreadyExistsFault" {
+			// A connection test for this endpoint/replication-instance pair is already
+			// in progress; fall through to polling for its result.
+		} else {
+			return fmt.Errorf("error testing DMS endpoint %q connection: %s", d.Id(), err)
+		}
+	}
+
+	var connection *dms.Connection
+	err = resource.Retry(timeout, func() *resource.RetryError {
+		response, err := conn.DescribeConnections(&dms.DescribeConnectionsInput{
+			Filters: []*dms.Filter{
+				{
+					Name:   aws.String("endpoint-arn"),
+					Values: []*string{aws.String(endpointArn)},
+				},
+				{
+					Name:   aws.String("replication-instance-arn"),
+					Values: []*string{aws.String(replicationInstanceArn)},
+				},
+			},
+		})
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if len(response.Connections) == 0 {
+			return resource.RetryableError(fmt.Errorf("DMS endpoint %q connection test has not started yet", d.Id()))
+		}
+
+		connection = response.Connections[0]
+		switch aws.StringValue(connection.Status) {
+		case "successful", "failed":
+			return nil
+		default:
+			return resource.RetryableError(fmt.Errorf("DMS endpoint %q connection test still in progress: %s", d.Id(), aws.StringValue(connection.Status)))
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("error waiting for DMS endpoint %q connection test: %s", d.Id(), err)
+	}
+
+	if aws.StringValue(connection.Status) != "successful" {
+		if rollbackOnFailure && allowRollback {
+			log.Printf("[DEBUG] Rolling back DMS endpoint %q after failed connection test", d.Id())
+			delErr := dmsRetryBackoff(d.Timeout(schema.TimeoutDelete), func() error {
+				_, err := conn.DeleteEndpoint(&dms.DeleteEndpointInput{EndpointArn: aws.String(endpointArn)})
+				return err
+			})
+			if delErr != nil {
+				log.Printf("[WARN] Error rolling back DMS endpoint %q after failed connection test: %s", d.Id(), delErr)
+			} else {
+				d.SetId("")
+			}
+		}
+
+		return fmt.Errorf("DMS endpoint %q connection test failed: %s", d.Id(), aws.StringValue(connection.LastFailureMessage))
+	}
+
+	return nil
+}
+
 func resourceAwsDmsEndpointSetState(d *schema.ResourceData, endpoint *dms.Endpoint) error {
 	d.SetId(*endpoint.EndpointIdentifier)
 
@@ -411,19 +1143,51 @@ func resourceAwsDmsEndpointSetState(d *schema.ResourceData, endpoint *dms.Endpoi
 		} else {
 			d.Set("service_access_role", "")
 		}
+		if err := d.Set("dynamodb_settings", flattenDmsDynamoDbSettings(endpoint.DynamoDbSettings)); err != nil {
+			return fmt.Errorf("error setting dynamodb_settings: %s", err)
+		}
 	case "s3":
 		if endpoint.S3Settings != nil {
 			d.Set("service_access_role", endpoint.S3Settings.ServiceAccessRoleArn)
 			d.Set("bucket_folder", endpoint.S3Settings.BucketFolder)
 			d.Set("bucket_name", endpoint.S3Settings.BucketName)
-			d.Set("extra_connection_attributes",
-				aws.String(fmt.Sprintf("compressionType=%s;csvDelimiter=%s;csvRowDelimiter=%s",
-					*endpoint.S3Settings.CompressionType, *endpoint.S3Settings.CsvDelimiter, *endpoint.S3Settings.CsvRowDelimiter)))
 		} else {
 			d.Set("service_access_role", "")
 			d.Set("bucket_folder", "")
 			d.Set("bucket_name", "")
-			d.Set("extra_connection_attributes", "")
+		}
+		s3SettingsList := flattenDmsS3Settings(endpoint.S3Settings)
+		// DMS doesn't return the S3-compatible overrides, so preserve whatever
+		// is already in the config/state for those fields.
+		if cur, ok := d.GetOk("s3_settings"); ok {
+			curList := cur.([]interface{})
+			if len(curList) > 0 && curList[0] != nil && len(s3SettingsList) > 0 {
+				curM := curList[0].(map[string]interface{})
+				s3SettingsList[0]["service_endpoint_url"] = curM["service_endpoint_url"]
+				s3SettingsList[0]["region_override"] = curM["region_override"]
+				s3SettingsList[0]["force_path_style"] = curM["force_path_style"]
+				s3SettingsList[0]["disable_ssl"] = curM["disable_ssl"]
+				s3SettingsList[0]["access_key_id"] = curM["access_key_id"]
+				s3SettingsList[0]["secret_access_key"] = curM["secret_access_key"]
+			}
+		}
+		if err := d.Set("s3_settings", s3SettingsList); err != nil {
+			return fmt.Errorf("error setting s3_settings: %s", err)
+		}
+		// s3_settings fully replaced the old extra_connection_attributes string
+		// for this engine, so clear it rather than leaving a stale value in state.
+		d.Set("extra_connection_attributes", "")
+	case "mongodb":
+		if err := d.Set("mongodb_settings", flattenDmsMongoDbSettings(endpoint.MongoDbSettings)); err != nil {
+			return fmt.Errorf("error setting mongodb_settings: %s", err)
+		}
+	case "kinesis":
+		if err := d.Set("kinesis_settings", flattenDmsKinesisSettings(endpoint.KinesisSettings)); err != nil {
+			return fmt.Errorf("error setting kinesis_settings: %s", err)
+		}
+	case "kafka":
+		if err := d.Set("kafka_settings", flattenDmsKafkaSettings(endpoint.KafkaSettings)); err != nil {
+			return fmt.Errorf("error setting kafka_settings: %s", err)
 		}
 	default:
 		d.Set("database_name", endpoint.DatabaseName)